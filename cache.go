@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
+)
+
+// cacheEntry holds the last successfully fetched value for a cache key.
+type cacheEntry struct {
+	value     any
+	fetchedAt time.Time
+}
+
+// fetchFunc fetches the current value for a cache key from the Synergy
+// Wholesale API.
+type fetchFunc func(ctx context.Context) (any, error)
+
+// Cache serves SOAP responses keyed by operation, coalescing concurrent
+// refreshes of the same key with singleflight and serving stale data while
+// a refresh is in flight so scrape latency doesn't depend on Synergy's API
+// latency. It implements prometheus.Collector so its instrumentation can be
+// folded into whatever Collector embeds it.
+type Cache struct {
+	ttl      time.Duration
+	maxStale time.Duration
+
+	group singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+
+	ageSeconds      *prometheus.GaugeVec
+	refreshTotal    *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+}
+
+// NewCache returns a Cache that serves entries fresh for ttl and, once past
+// ttl, stale (while refreshing in the background) until maxStale.
+func NewCache(ttl, maxStale time.Duration) *Cache {
+	return &Cache{
+		ttl:      ttl,
+		maxStale: maxStale,
+		entries:  make(map[string]cacheEntry),
+		ageSeconds: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "synergywholesale_cache_age_seconds",
+				Help: "Age of the cached response for a cache key, in seconds",
+			},
+			[]string{"key"},
+		),
+		refreshTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "synergywholesale_cache_refresh_total",
+				Help: "Count of cache refresh attempts by result",
+			},
+			[]string{"key", "result"},
+		),
+		requestDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name: "synergywholesale_api_request_duration_seconds",
+				Help: "Duration of Synergy Wholesale API requests made to populate the cache",
+			},
+			[]string{"key"},
+		),
+	}
+}
+
+// Describe and Collect make Cache itself a prometheus.Collector, so a
+// Collector that embeds one can just forward to it.
+func (cache *Cache) Describe(ch chan<- *prometheus.Desc) {
+	cache.ageSeconds.Describe(ch)
+	cache.refreshTotal.Describe(ch)
+	cache.requestDuration.Describe(ch)
+}
+
+func (cache *Cache) Collect(ch chan<- prometheus.Metric) {
+	cache.mu.Lock()
+	for key, entry := range cache.entries {
+		cache.ageSeconds.WithLabelValues(key).Set(time.Since(entry.fetchedAt).Seconds())
+	}
+	cache.mu.Unlock()
+
+	cache.ageSeconds.Collect(ch)
+	cache.refreshTotal.Collect(ch)
+	cache.requestDuration.Collect(ch)
+}
+
+// Get returns the value for key, fetching it with fetch if there's no entry
+// yet. An entry younger than ttl is returned as-is. One older than ttl but
+// younger than maxStale is still returned immediately (serve stale while
+// revalidate) with a refresh kicked off in the background. Anything older
+// than maxStale blocks the caller on a fresh fetch. Concurrent callers for
+// the same key, foreground or background, share a single in-flight fetch.
+func (cache *Cache) Get(ctx context.Context, key string, fetch fetchFunc) (any, error) {
+	cache.mu.Lock()
+	entry, ok := cache.entries[key]
+	cache.mu.Unlock()
+
+	if !ok {
+		return cache.refresh(ctx, key, fetch)
+	}
+
+	age := time.Since(entry.fetchedAt)
+	if age < cache.ttl {
+		return entry.value, nil
+	}
+	if age < cache.maxStale {
+		go cache.refreshInBackground(key, fetch)
+		return entry.value, nil
+	}
+
+	return cache.refresh(ctx, key, fetch)
+}
+
+func (cache *Cache) refreshInBackground(key string, fetch fetchFunc) {
+	if _, err := cache.refresh(context.Background(), key, fetch); err != nil {
+		slog.Warn("Background cache refresh failed", "key", key, "error", err)
+	}
+}
+
+// refresh runs fetch, bounded to cache.ttl so a hung Synergy API call can
+// only ever block callers for that long. The bound matters more than usual
+// here: singleflight.Do hands the same in-flight call to every caller for
+// key, foreground and background alike, so without it one stuck request
+// would wedge every future Get for that key, not just its own caller.
+func (cache *Cache) refresh(ctx context.Context, key string, fetch fetchFunc) (any, error) {
+	ctx, cancel := context.WithTimeout(ctx, cache.ttl)
+	defer cancel()
+
+	value, err, _ := cache.group.Do(key, func() (any, error) {
+		start := time.Now()
+		value, err := fetch(ctx)
+		cache.requestDuration.WithLabelValues(key).Observe(time.Since(start).Seconds())
+
+		result := "success"
+		if err != nil {
+			result = "error"
+		}
+		cache.refreshTotal.WithLabelValues(key, result).Inc()
+
+		if err != nil {
+			return nil, err
+		}
+
+		cache.mu.Lock()
+		cache.entries[key] = cacheEntry{value: value, fetchedAt: time.Now()}
+		cache.mu.Unlock()
+
+		return value, nil
+	})
+
+	return value, err
+}
+
+// RunPeriodicRefresh refreshes every key in sources on its own jittered
+// ticker until ctx is cancelled, so entries are rarely, if ever, found
+// stale on the request path. It's meant for a long-lived Collector (the
+// default /metrics one); a /probe Collector is too short-lived to benefit
+// and starting this for one would just leak goroutines.
+func (cache *Cache) RunPeriodicRefresh(ctx context.Context, sources map[string]fetchFunc) {
+	for key, fetch := range sources {
+		go cache.runPeriodicRefresh(ctx, key, fetch)
+	}
+}
+
+func (cache *Cache) runPeriodicRefresh(ctx context.Context, key string, fetch fetchFunc) {
+	if _, err := cache.refresh(ctx, key, fetch); err != nil {
+		slog.Warn("Periodic cache refresh failed", "key", key, "error", err)
+	}
+
+	for {
+		timer := time.NewTimer(cache.ttl - jitter(cache.ttl))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			if _, err := cache.refresh(ctx, key, fetch); err != nil {
+				slog.Warn("Periodic cache refresh failed", "key", key, "error", err)
+			}
+		}
+	}
+}
+
+// jitter returns a random duration in [0, ttl/4), so periodic refreshes of
+// many cache keys don't all land on the API in the same instant.
+func jitter(ttl time.Duration) time.Duration {
+	spread := ttl / 4
+	if spread <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(spread)))
+}
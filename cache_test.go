@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingFetch returns a fetchFunc that records how many times it was
+// called and returns the running call count as its value.
+func countingFetch(calls *int64) fetchFunc {
+	return func(ctx context.Context) (any, error) {
+		return atomic.AddInt64(calls, 1), nil
+	}
+}
+
+func TestCache_Get_FreshEntryServedWithoutRefetch(t *testing.T) {
+	cache := NewCache(time.Hour, 2*time.Hour)
+	var calls int64
+	fetch := countingFetch(&calls)
+
+	first, err := cache.Get(context.Background(), "key", fetch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	second, err := cache.Get(context.Background(), "key", fetch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if first != second {
+		t.Fatalf("expected a fresh entry to be served unchanged, got %v then %v", first, second)
+	}
+	if got := atomic.LoadInt64(&calls); got != 1 {
+		t.Fatalf("expected exactly 1 fetch, got %d", got)
+	}
+}
+
+func TestCache_Get_StaleEntryServedImmediatelyWhileRefreshing(t *testing.T) {
+	cache := NewCache(20*time.Millisecond, time.Hour)
+	var calls int64
+	refreshed := make(chan struct{}, 1)
+	fetch := func(ctx context.Context) (any, error) {
+		n := atomic.AddInt64(&calls, 1)
+		if n > 1 {
+			refreshed <- struct{}{}
+		}
+		return n, nil
+	}
+
+	if _, err := cache.Get(context.Background(), "key", fetch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond) // older than ttl, still within maxStale
+
+	start := time.Now()
+	value, err := cache.Get(context.Background(), "key", fetch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Fatalf("expected a stale Get to return immediately without waiting on a refresh, took %v", elapsed)
+	}
+	if value.(int64) != 1 {
+		t.Fatalf("expected the stale Get to return the old value, got %v", value)
+	}
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("expected a background refresh to run after serving stale data")
+	}
+	if got := atomic.LoadInt64(&calls); got != 2 {
+		t.Fatalf("expected exactly 2 fetches (initial + background refresh), got %d", got)
+	}
+}
+
+func TestCache_Get_ExpiredEntryBlocksOnFreshFetch(t *testing.T) {
+	cache := NewCache(10*time.Millisecond, 20*time.Millisecond)
+	var calls int64
+	fetch := countingFetch(&calls)
+
+	if _, err := cache.Get(context.Background(), "key", fetch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond) // older than maxStale
+
+	value, err := cache.Get(context.Background(), "key", fetch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value.(int64) != 2 {
+		t.Fatalf("expected a fresh fetch once an entry is older than maxStale, got %v", value)
+	}
+	if got := atomic.LoadInt64(&calls); got != 2 {
+		t.Fatalf("expected exactly 2 fetches, got %d", got)
+	}
+}
+
+func TestCache_Get_CoalescesConcurrentFetches(t *testing.T) {
+	cache := NewCache(time.Hour, 2*time.Hour)
+	var calls int64
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (any, error) {
+		atomic.AddInt64(&calls, 1)
+		<-release
+		return "value", nil
+	}
+
+	const concurrency = 20
+	results := make([]any, concurrency)
+	errs := make([]error, concurrency)
+
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := range concurrency {
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = cache.Get(context.Background(), "key", fetch)
+		}(i)
+	}
+
+	time.Sleep(50 * time.Millisecond) // let every goroutine reach the in-flight fetch
+	close(release)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("goroutine %d: unexpected error: %v", i, err)
+		}
+		if results[i] != "value" {
+			t.Fatalf("goroutine %d: got %v, want %q", i, results[i], "value")
+		}
+	}
+	if got := atomic.LoadInt64(&calls); got != 1 {
+		t.Fatalf("expected concurrent Get calls for the same key to be coalesced into 1 fetch, got %d", got)
+	}
+}
+
+func TestCache_Get_ReturnsFetchError(t *testing.T) {
+	cache := NewCache(time.Hour, 2*time.Hour)
+	wantErr := errors.New("boom")
+	fetch := func(ctx context.Context) (any, error) {
+		return nil, wantErr
+	}
+
+	if _, err := cache.Get(context.Background(), "key", fetch); !errors.Is(err, wantErr) {
+		t.Fatalf("got error %v, want %v", err, wantErr)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the structure of the file passed via --config.file. It defines
+// the credentials for every reseller the exporter is allowed to probe, and
+// the set of modules that determine what gets collected for a given probe.
+type Config struct {
+	Resellers map[string]ResellerConfig `yaml:"resellers"`
+	Modules   map[string]ModuleConfig   `yaml:"modules"`
+}
+
+// ResellerConfig holds the credentials for a single Synergy Wholesale
+// reseller account. The map key used in Config.Resellers is the target
+// value Prometheus passes via ?target=<key>.
+type ResellerConfig struct {
+	APIKey     string `yaml:"api_key"`
+	ResellerID string `yaml:"reseller_id"`
+}
+
+// ModuleConfig controls which SOAP operations a probe invokes, and by
+// extension which metrics get exported for it. A probe with no module
+// specified uses the "default" module.
+type ModuleConfig struct {
+	Operations []string `yaml:"operations"`
+}
+
+// loadConfig reads and parses the YAML file at path.
+func loadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	if _, ok := cfg.Modules["default"]; !ok {
+		if cfg.Modules == nil {
+			cfg.Modules = map[string]ModuleConfig{}
+		}
+		cfg.Modules["default"] = ModuleConfig{Operations: []string{"listDomains"}}
+	}
+
+	return &cfg, nil
+}
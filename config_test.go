@@ -0,0 +1,121 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeConfigFile(t, `
+resellers:
+  acme:
+    api_key: api-key
+    reseller_id: "12345"
+modules:
+  default:
+    operations:
+      - listDomains
+      - listSSLCertificates
+  ssl-only:
+    operations:
+      - listSSLCertificates
+`)
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := &Config{
+		Resellers: map[string]ResellerConfig{
+			"acme": {APIKey: "api-key", ResellerID: "12345"},
+		},
+		Modules: map[string]ModuleConfig{
+			"default":  {Operations: []string{opListDomains, opListSSLCertificates}},
+			"ssl-only": {Operations: []string{opListSSLCertificates}},
+		},
+	}
+
+	if !reflect.DeepEqual(cfg, want) {
+		t.Fatalf("got %+v, want %+v", cfg, want)
+	}
+}
+
+func TestLoadConfig_InjectsDefaultModuleWhenMissing(t *testing.T) {
+	path := writeConfigFile(t, `
+resellers:
+  acme:
+    api_key: api-key
+`)
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	module, ok := cfg.Modules["default"]
+	if !ok {
+		t.Fatal("expected a synthesized \"default\" module")
+	}
+	want := ModuleConfig{Operations: []string{"listDomains"}}
+	if !reflect.DeepEqual(module, want) {
+		t.Fatalf("got default module %+v, want %+v", module, want)
+	}
+}
+
+func TestLoadConfig_PreservesExplicitDefaultModule(t *testing.T) {
+	path := writeConfigFile(t, `
+resellers:
+  acme:
+    api_key: api-key
+modules:
+  default:
+    operations:
+      - listHosting
+`)
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := ModuleConfig{Operations: []string{opListHosting}}
+	if !reflect.DeepEqual(cfg.Modules["default"], want) {
+		t.Fatalf("got default module %+v, want %+v", cfg.Modules["default"], want)
+	}
+}
+
+func TestLoadConfig_MissingFile(t *testing.T) {
+	_, err := loadConfig(filepath.Join(t.TempDir(), "does-not-exist.yaml"))
+	if err == nil {
+		t.Fatal("expected an error for a missing config file")
+	}
+	if !strings.Contains(err.Error(), "failed to read config file") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLoadConfig_InvalidYAML(t *testing.T) {
+	path := writeConfigFile(t, "resellers: [this is not a map")
+
+	_, err := loadConfig(path)
+	if err == nil {
+		t.Fatal("expected an error for invalid YAML")
+	}
+	if !strings.Contains(err.Error(), "failed to parse config file") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -1,34 +1,46 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	api "github.com/yungwood/synergy-wholesale-exporter/synergywholesaleapi"
+	"golang.org/x/sync/errgroup"
 )
 
 var version = "development"
 
 var (
-	listDomainsResponse api.ListDomainsResponse
-	cacheExpires        int64
+	resellerID    = flag.String("reseller-id", "", "Synergy Wholesale Reseller ID")
+	apiKey        = flag.String("apikey", "", "Synergy Wholesale API Key")
+	listenAddress = flag.String("address", ":8080", "listening port for api")
+	printVersion  = flag.Bool("version", false, "print version and exit")
+	debugLogging  = flag.Bool("debug", false, "enable debug logging")
+	jsonLogging   = flag.Bool("json", false, "output logging in JSON format")
+	configFile    = flag.String("config.file", "", "path to a YAML config file enabling the multi-target /probe endpoint")
+
+	cacheTTLSeconds      = flag.Int64("cache.ttl", 3600, "seconds a cached API response is served without a refresh")
+	cacheMaxStaleSeconds = flag.Int64("cache.max-stale", 21600, "seconds a cached API response may be served stale (refreshing in the background) before a scrape blocks on a fresh fetch")
 )
 
+// Each flag here toggles one of the named sub-collectors, node_exporter
+// style. defaultModuleFromFlags maps each flag to the SOAP operation it
+// drives, so the default /metrics module can be built straight from them.
 var (
-	resellerID      = flag.String("reseller-id", "", "Synergy Wholesale Reseller ID")
-	apiKey          = flag.String("apikey", "", "Synergy Wholesale API Key")
-	listenAddress   = flag.String("address", ":8080", "listening port for api")
-	printVersion    = flag.Bool("version", false, "print version and exit")
-	debugLogging    = flag.Bool("debug", false, "enable debug logging")
-	jsonLogging     = flag.Bool("json", false, "output logging in JSON format")
-	cacheTTLSeconds = flag.Int64("ttl", 3600, "cache TTL value in seconds")
+	collectorDomains   = flag.Bool("collector.domains", true, "Collect domain registration metrics (listDomains)")
+	collectorSSL       = flag.Bool("collector.ssl", true, "Collect SSL certificate metrics (listSSLCertificates)")
+	collectorHosting   = flag.Bool("collector.hosting", true, "Collect hosting service metrics (listHosting)")
+	collectorContacts  = flag.Bool("collector.contacts", true, "Collect domain contact/lock metrics (domainInfo)")
+	collectorTransfers = flag.Bool("collector.transfers", true, "Collect domain transfer state metrics (transferStatus)")
 )
 
 var (
@@ -41,15 +53,84 @@ var (
 	)
 )
 
+// operation keys, shared between the module config and the operation field of
+// subCollectors' entries.
+const (
+	opListDomains         = "listDomains"
+	opListSSLCertificates = "listSSLCertificates"
+	opListHosting         = "listHosting"
+	opDomainInfo          = "domainInfo"
+	opTransferStatus      = "transferStatus"
+)
+
+const (
+	// maxConcurrentDomainCalls bounds how many domainInfo/transferStatus
+	// calls fetchContacts/fetchTransfers have in flight at once, so warming
+	// the cache for a reseller with hundreds of domains doesn't also open
+	// hundreds of connections at once.
+	maxConcurrentDomainCalls = 10
+
+	// perDomainCallTimeout bounds a single domainInfo/transferStatus call.
+	// It's deliberately separate from the cache's own refresh timeout
+	// (cache.ttl) so one slow domain can't eat the whole refresh's budget.
+	perDomainCallTimeout = 15 * time.Second
+)
+
+// defaultModuleFromFlags builds the module used by the default /metrics
+// endpoint from the --collector.* flags, so toggling a flag behaves the same
+// way disabling an operation in a --config.file module would.
+func defaultModuleFromFlags() ModuleConfig {
+	var operations []string
+	if *collectorDomains {
+		operations = append(operations, opListDomains)
+	}
+	if *collectorSSL {
+		operations = append(operations, opListSSLCertificates)
+	}
+	if *collectorHosting {
+		operations = append(operations, opListHosting)
+	}
+	if *collectorContacts {
+		operations = append(operations, opDomainInfo)
+	}
+	if *collectorTransfers {
+		operations = append(operations, opTransferStatus)
+	}
+	return ModuleConfig{Operations: operations}
+}
+
+// Collector is bound to a single reseller account. The default /metrics
+// endpoint registers one Collector built from the exporter-wide flags; the
+// /probe endpoint builds a fresh one per request from the target's
+// credentials in the config file.
 type Collector struct {
+	client *api.Client
+	module ModuleConfig
+	cache  *Cache
+
 	domainAutoRenew      *prometheus.Desc
 	domainExpiry         *prometheus.Desc
 	domainNameServer     *prometheus.Desc
 	domainDNSSECKeyCount *prometheus.Desc
+
+	sslCertExpiry *prometheus.Desc
+
+	hostingExpiry *prometheus.Desc
+
+	contactExpiry *prometheus.Desc
+	domainLock    *prometheus.Desc
+
+	domainTransferState *prometheus.Desc
+
+	scrapeDuration *prometheus.Desc
+	scrapeSuccess  *prometheus.Desc
 }
 
-func newCollector() *Collector {
+func newCollector(resellerID, apiKey string, module ModuleConfig, cacheTTL, cacheMaxStale time.Duration) *Collector {
 	return &Collector{
+		client: api.NewClient(apiKey, resellerID),
+		module: module,
+		cache:  NewCache(cacheTTL, cacheMaxStale),
 		domainAutoRenew: prometheus.NewDesc("domain_auto_renew_enable",
 			"Domain auto-renewal status",
 			[]string{"domain"},
@@ -70,20 +151,126 @@ func newCollector() *Collector {
 			[]string{"domain", "name_server_info"},
 			nil,
 		),
+		sslCertExpiry: prometheus.NewDesc("ssl_cert_expiry_timestamp_seconds",
+			"SSL certificate expiry timestamp in seconds",
+			[]string{"domain", "ca", "product"},
+			nil,
+		),
+		hostingExpiry: prometheus.NewDesc("hosting_service_expiry_timestamp_seconds",
+			"Hosting service expiry timestamp in seconds",
+			[]string{"domain", "product"},
+			nil,
+		),
+		contactExpiry: prometheus.NewDesc("domain_contact_expiry_timestamp_seconds",
+			"Domain contact role expiry timestamp in seconds",
+			[]string{"domain", "role"},
+			nil,
+		),
+		domainLock: prometheus.NewDesc("domain_lock_status",
+			"Domain registrar lock status",
+			[]string{"domain"},
+			nil,
+		),
+		domainTransferState: prometheus.NewDesc("domain_transfer_state",
+			"Domain transfer state",
+			[]string{"domain", "state"},
+			nil,
+		),
+		scrapeDuration: prometheus.NewDesc("synergywholesale_scrape_duration_seconds",
+			"Time taken for a collector's SOAP call(s) to complete",
+			[]string{"collector"},
+			nil,
+		),
+		scrapeSuccess: prometheus.NewDesc("synergywholesale_scrape_success",
+			"Whether a collector's most recent scrape succeeded",
+			[]string{"collector"},
+			nil,
+		),
+	}
+}
+
+// collectsOperation reports whether op is enabled for this collector's
+// module. An empty operation list means "collect everything" so the default
+// /metrics collector keeps working without a module configured.
+func (collector *Collector) collectsOperation(op string) bool {
+	if len(collector.module.Operations) == 0 {
+		return true
+	}
+	for _, configured := range collector.module.Operations {
+		if configured == op {
+			return true
+		}
 	}
+	return false
 }
 
 func (collector *Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- collector.domainAutoRenew
 	ch <- collector.domainExpiry
 	ch <- collector.domainNameServer
+	ch <- collector.domainDNSSECKeyCount
+	ch <- collector.sslCertExpiry
+	ch <- collector.hostingExpiry
+	ch <- collector.contactExpiry
+	ch <- collector.domainLock
+	ch <- collector.domainTransferState
+	ch <- collector.scrapeDuration
+	ch <- collector.scrapeSuccess
+	collector.cache.Describe(ch)
+}
+
+// subCollectors lists every named collector this exporter knows how to run.
+// operation gates it against the module config; fn does the actual work.
+func (collector *Collector) subCollectors() []struct {
+	name      string
+	operation string
+	fn        func(ctx context.Context, ch chan<- prometheus.Metric) error
+} {
+	return []struct {
+		name      string
+		operation string
+		fn        func(ctx context.Context, ch chan<- prometheus.Metric) error
+	}{
+		{"domains", opListDomains, collector.collectDomains},
+		{"ssl", opListSSLCertificates, collector.collectSSL},
+		{"hosting", opListHosting, collector.collectHosting},
+		{"contacts", opDomainInfo, collector.collectContacts},
+		{"transfers", opTransferStatus, collector.collectTransfers},
+	}
 }
 
 func (collector *Collector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
 
-	listDomainsResp := getDomains()
-	for _, domain := range listDomainsResp.Return.DomainList {
+	for _, sub := range collector.subCollectors() {
+		if !collector.collectsOperation(sub.operation) {
+			continue
+		}
+
+		start := time.Now()
+		err := sub.fn(ctx, ch)
+		duration := time.Since(start).Seconds()
+
+		success := 1.0
+		if err != nil {
+			success = 0
+			slog.Error("Collector failed", "collector", sub.name, "error", err)
+		}
+
+		ch <- prometheus.MustNewConstMetric(collector.scrapeDuration, prometheus.GaugeValue, duration, sub.name)
+		ch <- prometheus.MustNewConstMetric(collector.scrapeSuccess, prometheus.GaugeValue, success, sub.name)
+	}
+
+	collector.cache.Collect(ch)
+}
+
+func (collector *Collector) collectDomains(ctx context.Context, ch chan<- prometheus.Metric) error {
+	listDomainsResp, err := collector.getDomains(ctx)
+	if err != nil {
+		return err
+	}
 
+	for _, domain := range listDomainsResp.Return.DomainList {
 		// skip domains where api status != OK they are usually old/deleted
 		if domain.Status != "OK" {
 			continue
@@ -117,40 +304,282 @@ func (collector *Collector) Collect(ch chan<- prometheus.Metric) {
 			)
 		}
 	}
+
+	return nil
+}
+
+func (collector *Collector) collectSSL(ctx context.Context, ch chan<- prometheus.Metric) error {
+	value, err := collector.cache.Get(ctx, "ssl", collector.fetchSSL)
+	if err != nil {
+		return err
+	}
+	response := value.(api.ListSSLCertificatesResponse)
+
+	for _, cert := range response.Return.Certificates {
+		ch <- prometheus.MustNewConstMetric(
+			collector.sslCertExpiry,
+			prometheus.GaugeValue,
+			float64(cert.GetExpiryTimestamp()),
+			cert.Domain, cert.CA, cert.Product,
+		)
+	}
+
+	return nil
+}
+
+func (collector *Collector) collectHosting(ctx context.Context, ch chan<- prometheus.Metric) error {
+	value, err := collector.cache.Get(ctx, "hosting", collector.fetchHosting)
+	if err != nil {
+		return err
+	}
+	response := value.(api.ListHostingResponse)
+
+	for _, service := range response.Return.Services {
+		ch <- prometheus.MustNewConstMetric(
+			collector.hostingExpiry,
+			prometheus.GaugeValue,
+			float64(service.GetExpiryTimestamp()),
+			service.Domain, service.Product,
+		)
+	}
+
+	return nil
+}
+
+// collectContacts and collectTransfers both need a domain param, so instead
+// of listing globally like the other collectors they fetch and cache a
+// domain-keyed map (see fetchContacts/fetchTransfers) built from one
+// domainInfo/transferStatus call per domain in the (cached) domain list.
+
+func (collector *Collector) collectContacts(ctx context.Context, ch chan<- prometheus.Metric) error {
+	listDomainsResp, err := collector.getDomains(ctx)
+	if err != nil {
+		return err
+	}
+
+	value, err := collector.cache.Get(ctx, "contacts", collector.fetchContacts)
+	if err != nil {
+		return err
+	}
+	contactsByDomain := value.(map[string]api.DomainInfoResponse)
+
+	for _, domain := range listDomainsResp.Return.DomainList {
+		if domain.Status != "OK" {
+			continue
+		}
+
+		response, ok := contactsByDomain[domain.DomainName]
+		if !ok {
+			continue
+		}
+
+		lockStatus := 0.0
+		if response.Return.IsLocked() {
+			lockStatus = 1.0
+		}
+		ch <- prometheus.MustNewConstMetric(collector.domainLock, prometheus.GaugeValue, lockStatus, domain.DomainName)
+
+		for _, contact := range response.Return.Contacts {
+			ch <- prometheus.MustNewConstMetric(
+				collector.contactExpiry,
+				prometheus.GaugeValue,
+				float64(contact.GetExpiryTimestamp()),
+				domain.DomainName, contact.Role,
+			)
+		}
+	}
+
+	return nil
 }
 
-func getDomains() api.ListDomainsResponse {
-	if cacheExpires > time.Now().Unix() {
-		return listDomainsResponse
+func (collector *Collector) collectTransfers(ctx context.Context, ch chan<- prometheus.Metric) error {
+	listDomainsResp, err := collector.getDomains(ctx)
+	if err != nil {
+		return err
 	}
 
-	slog.Info("Sending listDomains request to Synergy Wholesale API", "reseller_id", *resellerID)
+	value, err := collector.cache.Get(ctx, "transfers", collector.fetchTransfers)
+	if err != nil {
+		return err
+	}
+	transfersByDomain := value.(map[string]api.TransferStatusResponse)
+
+	for _, domain := range listDomainsResp.Return.DomainList {
+		if domain.Status != "OK" {
+			continue
+		}
+
+		response, ok := transfersByDomain[domain.DomainName]
+		if !ok {
+			continue
+		}
 
-	request := api.ListDomainsRequest{
-		ApiKey:     *apiKey,
-		ResellerID: *resellerID,
+		ch <- prometheus.MustNewConstMetric(
+			collector.domainTransferState,
+			prometheus.GaugeValue,
+			1,
+			domain.DomainName, response.Return.State,
+		)
 	}
 
-	data, err := api.SendSOAPRequest(request)
+	return nil
+}
+
+func (collector *Collector) getDomains(ctx context.Context) (api.ListDomainsResponse, error) {
+	value, err := collector.cache.Get(ctx, "domains", collector.fetchDomains)
 	if err != nil {
-		fmt.Printf("Error sending SOAP request: %v\n", err)
-		return api.ListDomainsResponse{}
+		return api.ListDomainsResponse{}, err
 	}
+	return value.(api.ListDomainsResponse), nil
+}
+
+// fetchDomains, fetchSSL and fetchHosting are the raw, uncached API calls
+// backing the "domains", "ssl" and "hosting" cache keys; they're also what
+// the periodic background refresher in main() calls directly.
+func (collector *Collector) fetchDomains(ctx context.Context) (any, error) {
+	slog.Info("Sending listDomains request to Synergy Wholesale API", "reseller_id", collector.client.ResellerID)
 
-	// Prepare the response struct
 	var response api.ListDomainsResponse
+	if err := collector.client.Call(ctx, "listDomains", nil, &response); err != nil {
+		return nil, fmt.Errorf("listDomains: %w", err)
+	}
+	return response, nil
+}
+
+func (collector *Collector) fetchSSL(ctx context.Context) (any, error) {
+	slog.Info("Sending listSSLCertificates request to Synergy Wholesale API", "reseller_id", collector.client.ResellerID)
 
-	// Unmarshal the response
-	err2 := api.UnmarshalSOAPResponse(data, &response)
-	if err2 != nil {
-		fmt.Printf("Error: %v\n", err2)
-		return api.ListDomainsResponse{}
+	response, err := api.ListSSLCertificates(ctx, collector.client)
+	if err != nil {
+		return nil, fmt.Errorf("listSSLCertificates: %w", err)
 	}
+	return response, nil
+}
 
-	listDomainsResponse = response
-	cacheExpires = time.Now().Unix() + *cacheTTLSeconds
+func (collector *Collector) fetchHosting(ctx context.Context) (any, error) {
+	slog.Info("Sending listHosting request to Synergy Wholesale API", "reseller_id", collector.client.ResellerID)
 
-	return response
+	response, err := api.ListHosting(ctx, collector.client)
+	if err != nil {
+		return nil, fmt.Errorf("listHosting: %w", err)
+	}
+	return response, nil
+}
+
+// fetchContacts and fetchTransfers back the "contacts"/"transfers" cache
+// keys. domainInfo and transferStatus take one domain at a time, so these
+// fan out over the (cached) domain list with a bounded worker pool instead
+// of one sequential call per domain, and cache the resulting domain->response
+// map just like fetchDomains/fetchSSL/fetchHosting cache theirs. A failure
+// for one domain is logged and skipped rather than failing the whole
+// refresh, matching how the collector functions always treated per-domain
+// errors.
+func (collector *Collector) fetchContacts(ctx context.Context) (any, error) {
+	listDomainsResp, err := collector.getDomains(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listDomains: %w", err)
+	}
+
+	results := make(map[string]api.DomainInfoResponse)
+	var mu sync.Mutex
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(maxConcurrentDomainCalls)
+
+	for _, domain := range listDomainsResp.Return.DomainList {
+		if domain.Status != "OK" {
+			continue
+		}
+		domain := domain
+
+		group.Go(func() error {
+			callCtx, cancel := context.WithTimeout(groupCtx, perDomainCallTimeout)
+			defer cancel()
+
+			slog.Info("Sending domainInfo request to Synergy Wholesale API", "reseller_id", collector.client.ResellerID, "domain", domain.DomainName)
+			response, err := api.GetDomainInfo(callCtx, collector.client, domain.DomainName)
+			if err != nil {
+				slog.Error("domainInfo failed", "domain", domain.DomainName, "error", err)
+				return nil
+			}
+
+			mu.Lock()
+			results[domain.DomainName] = response
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, fmt.Errorf("domainInfo: %w", err)
+	}
+
+	return results, nil
+}
+
+func (collector *Collector) fetchTransfers(ctx context.Context) (any, error) {
+	listDomainsResp, err := collector.getDomains(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listDomains: %w", err)
+	}
+
+	results := make(map[string]api.TransferStatusResponse)
+	var mu sync.Mutex
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(maxConcurrentDomainCalls)
+
+	for _, domain := range listDomainsResp.Return.DomainList {
+		if domain.Status != "OK" {
+			continue
+		}
+		domain := domain
+
+		group.Go(func() error {
+			callCtx, cancel := context.WithTimeout(groupCtx, perDomainCallTimeout)
+			defer cancel()
+
+			slog.Info("Sending transferStatus request to Synergy Wholesale API", "reseller_id", collector.client.ResellerID, "domain", domain.DomainName)
+			response, err := api.TransferStatus(callCtx, collector.client, domain.DomainName)
+			if err != nil {
+				slog.Error("transferStatus failed", "domain", domain.DomainName, "error", err)
+				return nil
+			}
+
+			mu.Lock()
+			results[domain.DomainName] = response
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, fmt.Errorf("transferStatus: %w", err)
+	}
+
+	return results, nil
+}
+
+// cacheSources lists the fetch for every cache key currently enabled by this
+// collector's module, gated by the same collectsOperation check Collect uses
+// for subCollectors. Without that gating a disabled collector would still be
+// refreshed forever in the background even though it's hidden from /metrics.
+func (collector *Collector) cacheSources() map[string]fetchFunc {
+	fetches := map[string]fetchFunc{
+		"domains":   collector.fetchDomains,
+		"ssl":       collector.fetchSSL,
+		"hosting":   collector.fetchHosting,
+		"contacts":  collector.fetchContacts,
+		"transfers": collector.fetchTransfers,
+	}
+
+	sources := make(map[string]fetchFunc)
+	for _, sub := range collector.subCollectors() {
+		if collector.collectsOperation(sub.operation) {
+			sources[sub.name] = fetches[sub.name]
+		}
+	}
+	return sources
 }
 
 func main() {
@@ -198,13 +627,33 @@ func main() {
 		os.Exit(1)
 	}
 
+	cacheTTL := time.Duration(*cacheTTLSeconds) * time.Second
+	cacheMaxStale := time.Duration(*cacheMaxStaleSeconds) * time.Second
+
 	// setup exporter
 	prometheusRegistry := prometheus.NewRegistry()
 	BuildInfo.WithLabelValues(version, runtime.Version()).Set(1)
-	collector := newCollector()
+	collector := newCollector(*resellerID, *apiKey, defaultModuleFromFlags(), cacheTTL, cacheMaxStale)
 	prometheusRegistry.MustRegister(BuildInfo, collector)
 	http.Handle("/metrics", promhttp.HandlerFor(prometheusRegistry, promhttp.HandlerOpts{}))
 
+	// proactively keep the default collector's cache warm so a scrape is
+	// never the thing that pays for a stale entry; a /probe collector is
+	// created fresh per request and isn't worth refreshing this way.
+	go collector.cache.RunPeriodicRefresh(context.Background(), collector.cacheSources())
+
+	// the /probe endpoint lets Prometheus scrape any reseller listed in
+	// --config.file using the standard Blackbox-exporter multi-target
+	// pattern (?target=<reseller>&module=<module>), via relabel_configs.
+	if *configFile != "" {
+		cfg, err := loadConfig(*configFile)
+		if err != nil {
+			slog.Error("Error loading config file", "error", err, "path", *configFile)
+			os.Exit(1)
+		}
+		http.Handle("/probe", probeHandler(cfg, cacheTTL, cacheMaxStale))
+	}
+
 	// add a readiness and liveness check endpoint (return blank 200 OK response)
 	http.HandleFunc("/liveness", func(w http.ResponseWriter, r *http.Request) {})
 	http.HandleFunc("/readiness", func(w http.ResponseWriter, r *http.Request) {})
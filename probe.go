@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// probeHandler implements the Blackbox-exporter-style multi-target pattern:
+// Prometheus supplies the reseller to scrape via ?target=<resellerKey> and,
+// optionally, which module to run via ?module=<name>. Credentials for the
+// target are looked up in cfg rather than taken from exporter-wide flags, so
+// a single exporter instance can be scraped for many resellers.
+func probeHandler(cfg *Config, cacheTTL, cacheMaxStale time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		target := r.URL.Query().Get("target")
+		if target == "" {
+			http.Error(w, "target parameter is missing", http.StatusBadRequest)
+			return
+		}
+
+		moduleName := r.URL.Query().Get("module")
+		if moduleName == "" {
+			moduleName = "default"
+		}
+
+		reseller, ok := cfg.Resellers[target]
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown target %q", target), http.StatusBadRequest)
+			return
+		}
+
+		module, ok := cfg.Modules[moduleName]
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown module %q", moduleName), http.StatusBadRequest)
+			return
+		}
+
+		resellerID := resolveResellerID(target, reseller)
+
+		slog.Debug("Probing reseller", "target", target, "reseller_id", resellerID, "module", moduleName)
+
+		// A fresh registry per request keeps metrics scoped to this target,
+		// so a slow or failing probe for one reseller can never leak labels
+		// from another.
+		registry := prometheus.NewRegistry()
+		collector := newCollector(resellerID, reseller.APIKey, module, cacheTTL, cacheMaxStale)
+		registry.MustRegister(collector)
+
+		promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	}
+}
+
+// resolveResellerID returns the reseller ID to authenticate SOAP calls with:
+// reseller.ResellerID when the config sets one explicitly, or target itself
+// otherwise, so an operator can skip reseller_id in the config file when it
+// matches the Prometheus target label.
+func resolveResellerID(target string, reseller ResellerConfig) string {
+	if reseller.ResellerID != "" {
+		return reseller.ResellerID
+	}
+	return target
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProbeHandler_MissingTarget(t *testing.T) {
+	cfg := &Config{
+		Resellers: map[string]ResellerConfig{"acme": {APIKey: "api-key"}},
+		Modules:   map[string]ModuleConfig{"default": {Operations: []string{opListDomains}}},
+	}
+	handler := probeHandler(cfg, time.Hour, 2*time.Hour)
+
+	req := httptest.NewRequest(http.MethodGet, "/probe", nil)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if !strings.Contains(rec.Body.String(), "target parameter is missing") {
+		t.Fatalf("unexpected body: %s", rec.Body.String())
+	}
+}
+
+func TestProbeHandler_UnknownTarget(t *testing.T) {
+	cfg := &Config{
+		Resellers: map[string]ResellerConfig{"acme": {APIKey: "api-key"}},
+		Modules:   map[string]ModuleConfig{"default": {Operations: []string{opListDomains}}},
+	}
+	handler := probeHandler(cfg, time.Hour, 2*time.Hour)
+
+	req := httptest.NewRequest(http.MethodGet, "/probe?target=unknown", nil)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if !strings.Contains(rec.Body.String(), `unknown target "unknown"`) {
+		t.Fatalf("unexpected body: %s", rec.Body.String())
+	}
+}
+
+func TestProbeHandler_UnknownModule(t *testing.T) {
+	cfg := &Config{
+		Resellers: map[string]ResellerConfig{"acme": {APIKey: "api-key"}},
+		Modules:   map[string]ModuleConfig{"default": {Operations: []string{opListDomains}}},
+	}
+	handler := probeHandler(cfg, time.Hour, 2*time.Hour)
+
+	req := httptest.NewRequest(http.MethodGet, "/probe?target=acme&module=missing", nil)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if !strings.Contains(rec.Body.String(), `unknown module "missing"`) {
+		t.Fatalf("unexpected body: %s", rec.Body.String())
+	}
+}
+
+// TestProbeHandler_Serves checks the handler builds a registry and serves a
+// response without touching the network, by pointing the target's module at
+// an operation name no subCollector matches, so collectsOperation disables
+// every SOAP call.
+func TestProbeHandler_Serves(t *testing.T) {
+	cfg := &Config{
+		Resellers: map[string]ResellerConfig{"acme": {APIKey: "api-key"}},
+		Modules:   map[string]ModuleConfig{"default": {Operations: []string{"none"}}},
+	}
+	handler := probeHandler(cfg, time.Hour, 2*time.Hour)
+
+	req := httptest.NewRequest(http.MethodGet, "/probe?target=acme", nil)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d, body: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.Contains(ct, "text/plain") {
+		t.Fatalf("expected a Prometheus text exposition response, got Content-Type %q", ct)
+	}
+}
+
+func TestResolveResellerID(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		target   string
+		reseller ResellerConfig
+		want     string
+	}{
+		{
+			desc:     "explicit reseller id wins",
+			target:   "acme",
+			reseller: ResellerConfig{ResellerID: "12345"},
+			want:     "12345",
+		},
+		{
+			desc:     "falls back to the target key",
+			target:   "acme",
+			reseller: ResellerConfig{},
+			want:     "acme",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			if got := resolveResellerID(test.target, test.reseller); got != test.want {
+				t.Fatalf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
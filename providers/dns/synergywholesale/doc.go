@@ -0,0 +1,3 @@
+// Package synergywholesale implements a DNS provider for solving the DNS-01
+// challenge through Synergy Wholesale.
+package synergywholesale
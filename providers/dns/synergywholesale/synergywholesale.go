@@ -0,0 +1,196 @@
+package synergywholesale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/platform/config/env"
+	api "github.com/yungwood/synergy-wholesale-exporter/synergywholesaleapi"
+)
+
+// Environment variables names.
+const (
+	envNamespace = "SYNERGYWHOLESALE_"
+
+	EnvAPIKey     = envNamespace + "API_KEY"
+	EnvResellerID = envNamespace + "RESELLER_ID"
+
+	EnvTTL                = envNamespace + "TTL"
+	EnvPropagationTimeout = envNamespace + "PROPAGATION_TIMEOUT"
+	EnvPollingInterval    = envNamespace + "POLLING_INTERVAL"
+)
+
+// Config is used to configure the creation of the DNSProvider.
+type Config struct {
+	APIKey     string
+	ResellerID string
+
+	PropagationTimeout time.Duration
+	PollingInterval    time.Duration
+	TTL                int
+}
+
+// NewDefaultConfig returns a Config populated from the environment, falling
+// back to lego's usual defaults for timing.
+func NewDefaultConfig() *Config {
+	return &Config{
+		TTL:                env.GetOrDefaultInt(EnvTTL, 300),
+		PropagationTimeout: env.GetOrDefaultSecond(EnvPropagationTimeout, 2*time.Minute),
+		PollingInterval:    env.GetOrDefaultSecond(EnvPollingInterval, 4*time.Second),
+	}
+}
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	config *Config
+	client *api.Client
+
+	// recordIDs remembers the record_id addDNSRecord returned for a given
+	// challenge token, so CleanUp can delete it directly instead of
+	// re-listing the zone.
+	recordIDs   map[string]string
+	recordIDsMu sync.Mutex
+}
+
+var _ challenge.Provider = (*DNSProvider)(nil)
+
+// NewDNSProvider returns a DNSProvider instance configured for Synergy
+// Wholesale. Credentials are read from the environment variables
+// SYNERGYWHOLESALE_API_KEY and SYNERGYWHOLESALE_RESELLER_ID.
+func NewDNSProvider() (*DNSProvider, error) {
+	values, err := env.Get(EnvAPIKey, EnvResellerID)
+	if err != nil {
+		return nil, fmt.Errorf("synergywholesale: %w", err)
+	}
+
+	config := NewDefaultConfig()
+	config.APIKey = values[EnvAPIKey]
+	config.ResellerID = values[EnvResellerID]
+
+	return NewDNSProviderConfig(config)
+}
+
+// NewDNSProviderConfig returns a DNSProvider instance configured for Synergy
+// Wholesale.
+func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
+	if config == nil {
+		return nil, errors.New("synergywholesale: the configuration of the DNS provider is nil")
+	}
+
+	if config.APIKey == "" {
+		return nil, errors.New("synergywholesale: API key is missing")
+	}
+	if config.ResellerID == "" {
+		return nil, errors.New("synergywholesale: reseller ID is missing")
+	}
+
+	return &DNSProvider{
+		config:    config,
+		client:    api.NewClient(config.APIKey, config.ResellerID),
+		recordIDs: make(map[string]string),
+	}, nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS
+// propagation.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return d.config.PropagationTimeout, d.config.PollingInterval
+}
+
+// Present creates a TXT record to fulfill the DNS-01 challenge.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	fqdn, value := dns01.GetRecord(domain, keyAuth)
+
+	zone, subDomain, err := d.findZoneAndSubDomain(fqdn)
+	if err != nil {
+		return fmt.Errorf("synergywholesale: %w", err)
+	}
+
+	response, err := api.AddDNSRecord(context.Background(), d.client, zone, "TXT", subDomain, value, d.config.TTL, 0)
+	if err != nil {
+		return fmt.Errorf("synergywholesale: failed to add TXT record for %s: %w", fqdn, err)
+	}
+
+	d.recordIDsMu.Lock()
+	d.recordIDs[token] = response.Return.RecordID
+	d.recordIDsMu.Unlock()
+
+	return nil
+}
+
+// CleanUp removes the TXT record created by Present.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	fqdn, value := dns01.GetRecord(domain, keyAuth)
+
+	zone, subDomain, err := d.findZoneAndSubDomain(fqdn)
+	if err != nil {
+		return fmt.Errorf("synergywholesale: %w", err)
+	}
+
+	d.recordIDsMu.Lock()
+	recordID, ok := d.recordIDs[token]
+	delete(d.recordIDs, token)
+	d.recordIDsMu.Unlock()
+
+	if !ok {
+		recordID, err = d.findTXTRecordIDInZone(zone, subDomain, value)
+		if err != nil {
+			return fmt.Errorf("synergywholesale: %w", err)
+		}
+	}
+
+	_, err = api.DeleteDNSRecord(context.Background(), d.client, zone, recordID)
+	if err != nil {
+		return fmt.Errorf("synergywholesale: failed to delete TXT record for %s: %w", fqdn, err)
+	}
+
+	return nil
+}
+
+// findTXTRecordIDInZone falls back to a live listDNSZone lookup when
+// CleanUp has no record ID cached from Present, e.g. because the challenge
+// was serviced by a different process.
+func (d *DNSProvider) findTXTRecordIDInZone(zone, subDomain, value string) (string, error) {
+	zoneResponse, err := api.ListDNSZone(context.Background(), d.client, zone)
+	if err != nil {
+		return "", fmt.Errorf("failed to list DNS zone for %s: %w", zone, err)
+	}
+
+	return findTXTRecordID(zoneResponse.Return.Records, subDomain, value)
+}
+
+// lookupZone is dns01.FindZoneByFqdn by default; tests override it so they
+// don't depend on live DNS resolution.
+var lookupZone = dns01.FindZoneByFqdn
+
+// findZoneAndSubDomain resolves fqdn to the zone Synergy Wholesale manages
+// (the registered domain) and the record name relative to that zone, so a
+// challenge for foo.bar.example.com finds the example.com zone.
+func (d *DNSProvider) findZoneAndSubDomain(fqdn string) (zone, subDomain string, err error) {
+	authZone, err := lookupZone(fqdn)
+	if err != nil {
+		return "", "", fmt.Errorf("could not find zone for %s: %w", fqdn, err)
+	}
+
+	subDomain, err = dns01.ExtractSubDomain(fqdn, authZone)
+	if err != nil {
+		return "", "", err
+	}
+
+	return dns01.UnFqdn(authZone), subDomain, nil
+}
+
+func findTXTRecordID(records []api.DNSZoneRecord, host, value string) (string, error) {
+	for _, record := range records {
+		if record.Type == "TXT" && record.Host == host && record.Value == value {
+			return record.RecordID, nil
+		}
+	}
+
+	return "", fmt.Errorf("could not find TXT record for %s with value %s", host, value)
+}
@@ -0,0 +1,247 @@
+package synergywholesale
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+)
+
+// newFixtureServer starts a SOAP server that replies with the XML fixture
+// whose key is the method name (e.g. "addDNSRecord") found in the request
+// body, and points provider at it for the duration of the test.
+func newFixtureServer(t *testing.T, provider *DNSProvider, fixtures map[string]string) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("failed to read request body: %v", err)
+		}
+
+		for method, fixture := range fixtures {
+			if strings.Contains(string(body), "ns1:"+method) {
+				w.Header().Set("Content-Type", "text/xml")
+				_, _ = w.Write([]byte(fixture))
+				return
+			}
+		}
+
+		t.Fatalf("no fixture registered for request: %s", body)
+	}))
+	t.Cleanup(server.Close)
+
+	provider.client.Endpoint = server.URL
+}
+
+const addDNSRecordFixtureOK = `<?xml version="1.0" encoding="UTF-8"?>
+<SOAP-ENV:Envelope xmlns:SOAP-ENV="http://schemas.xmlsoap.org/soap/envelope/">
+  <SOAP-ENV:Body>
+    <ns1:addDNSRecordResponse xmlns:ns1="http://api.synergywholesale.com">
+      <return xsi:type="ns2:Map">
+        <status>OK</status>
+        <record_id>12345</record_id>
+      </return>
+    </ns1:addDNSRecordResponse>
+  </SOAP-ENV:Body>
+</SOAP-ENV:Envelope>`
+
+const addDNSRecordFixtureError = `<?xml version="1.0" encoding="UTF-8"?>
+<SOAP-ENV:Envelope xmlns:SOAP-ENV="http://schemas.xmlsoap.org/soap/envelope/">
+  <SOAP-ENV:Body>
+    <ns1:addDNSRecordResponse xmlns:ns1="http://api.synergywholesale.com">
+      <return xsi:type="ns2:Map">
+        <status>FAIL</status>
+        <errorMessage>Unknown domain</errorMessage>
+      </return>
+    </ns1:addDNSRecordResponse>
+  </SOAP-ENV:Body>
+</SOAP-ENV:Envelope>`
+
+const listDNSZoneFixtureTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<SOAP-ENV:Envelope xmlns:SOAP-ENV="http://schemas.xmlsoap.org/soap/envelope/">
+  <SOAP-ENV:Body>
+    <ns1:listDNSZoneResponse xmlns:ns1="http://api.synergywholesale.com">
+      <return xsi:type="ns2:Map">
+        <status>OK</status>
+        <recordList>
+          <item>
+            <record_id>12345</record_id>
+            <type>TXT</type>
+            <name>_acme-challenge</name>
+            <data>%s</data>
+            <ttl>300</ttl>
+          </item>
+        </recordList>
+      </return>
+    </ns1:listDNSZoneResponse>
+  </SOAP-ENV:Body>
+</SOAP-ENV:Envelope>`
+
+const deleteDNSRecordFixtureOK = `<?xml version="1.0" encoding="UTF-8"?>
+<SOAP-ENV:Envelope xmlns:SOAP-ENV="http://schemas.xmlsoap.org/soap/envelope/">
+  <SOAP-ENV:Body>
+    <ns1:deleteDNSRecordResponse xmlns:ns1="http://api.synergywholesale.com">
+      <return xsi:type="ns2:Map">
+        <status>OK</status>
+      </return>
+    </ns1:deleteDNSRecordResponse>
+  </SOAP-ENV:Body>
+</SOAP-ENV:Envelope>`
+
+func newTestConfig() *Config {
+	config := NewDefaultConfig()
+	config.APIKey = "api-key"
+	config.ResellerID = "reseller-id"
+	return config
+}
+
+// stubZoneLookup makes findZoneAndSubDomain treat "example.com." as the
+// authoritative zone for every fqdn, without depending on live DNS.
+func stubZoneLookup(t *testing.T) {
+	t.Helper()
+
+	original := lookupZone
+	lookupZone = func(fqdn string) (string, error) {
+		return "example.com.", nil
+	}
+	t.Cleanup(func() { lookupZone = original })
+}
+
+func TestNewDNSProviderConfig(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		config  *Config
+		wantErr string
+	}{
+		{
+			desc:   "success",
+			config: newTestConfig(),
+		},
+		{
+			desc:    "nil config",
+			config:  nil,
+			wantErr: "synergywholesale: the configuration of the DNS provider is nil",
+		},
+		{
+			desc:    "missing api key",
+			config:  &Config{ResellerID: "reseller-id"},
+			wantErr: "synergywholesale: API key is missing",
+		},
+		{
+			desc:    "missing reseller id",
+			config:  &Config{APIKey: "api-key"},
+			wantErr: "synergywholesale: reseller ID is missing",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := NewDNSProviderConfig(test.config)
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || err.Error() != test.wantErr {
+				t.Fatalf("got error %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestDNSProvider_Present(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		fixture string
+		wantErr bool
+	}{
+		{
+			desc:    "success",
+			fixture: addDNSRecordFixtureOK,
+		},
+		{
+			desc:    "api error",
+			fixture: addDNSRecordFixtureError,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			stubZoneLookup(t)
+
+			provider, err := NewDNSProviderConfig(newTestConfig())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			newFixtureServer(t, provider, map[string]string{"addDNSRecord": test.fixture})
+
+			err = provider.Present("example.com", "token", "key-auth")
+			if test.wantErr && err == nil {
+				t.Fatal("expected an error, got none")
+			}
+			if !test.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestDNSProvider_CleanUp_FallsBackToZoneSearch covers CleanUp called
+// without a matching Present call in this provider instance (e.g. the
+// challenge was serviced by a different process), which has no cached
+// record ID and must fall back to a listDNSZone lookup.
+func TestDNSProvider_CleanUp_FallsBackToZoneSearch(t *testing.T) {
+	stubZoneLookup(t)
+
+	_, value := dns01.GetRecord("example.com", "key-auth")
+
+	provider, err := NewDNSProviderConfig(newTestConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	newFixtureServer(t, provider, map[string]string{
+		"listDNSZone":     fmt.Sprintf(listDNSZoneFixtureTemplate, value),
+		"deleteDNSRecord": deleteDNSRecordFixtureOK,
+	})
+
+	if err := provider.CleanUp("example.com", "token", "key-auth"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// TestDNSProvider_CleanUp_UsesCachedRecordID covers the common case: CleanUp
+// reuses the record ID Present got back from addDNSRecord, without needing
+// a listDNSZone lookup at all. No "listDNSZone" fixture is registered, so
+// the test would fail on an unhandled SOAP call if CleanUp fell back to
+// searching the zone instead of using the cached ID.
+func TestDNSProvider_CleanUp_UsesCachedRecordID(t *testing.T) {
+	stubZoneLookup(t)
+
+	provider, err := NewDNSProviderConfig(newTestConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	newFixtureServer(t, provider, map[string]string{
+		"addDNSRecord":    addDNSRecordFixtureOK,
+		"deleteDNSRecord": deleteDNSRecordFixtureOK,
+	})
+
+	if err := provider.Present("example.com", "token", "key-auth"); err != nil {
+		t.Fatalf("unexpected error from Present: %v", err)
+	}
+	if err := provider.CleanUp("example.com", "token", "key-auth"); err != nil {
+		t.Fatalf("unexpected error from CleanUp: %v", err)
+	}
+
+	if _, ok := provider.recordIDs["token"]; ok {
+		t.Fatal("expected CleanUp to remove the token's cached record ID")
+	}
+}
@@ -0,0 +1,153 @@
+package synergywholesaleapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+)
+
+const defaultEndpoint = "https://api.synergywholesale.com"
+
+// Client is a generic dispatcher for the Synergy Wholesale SOAP API. Unlike
+// one hand-rolled MarshalXML per operation, it builds the "ns1:<method>"
+// envelope directly from a params map, so new operations are just a method
+// name and a map away.
+type Client struct {
+	APIKey     string
+	ResellerID string
+
+	// Endpoint overrides the default API URL; tests point it at a fixture
+	// server. HTTPClient overrides the default http.Client, e.g. to set a
+	// request timeout.
+	Endpoint   string
+	HTTPClient *http.Client
+}
+
+// NewClient returns a Client for the given reseller account.
+func NewClient(apiKey, resellerID string) *Client {
+	return &Client{
+		APIKey:     apiKey,
+		ResellerID: resellerID,
+	}
+}
+
+// Call invokes the SOAP method with params (apiKey/resellerID are added
+// automatically) and decodes the response into out, which should be a
+// pointer to one of this package's `*Response` types. ctx governs the
+// request's deadline/cancellation. Call returns an *APIError when the
+// response's "return" status is present and not "OK".
+func (c *Client) Call(ctx context.Context, method string, params map[string]any, out any) error {
+	allParams := make(map[string]any, len(params)+2)
+	allParams["apiKey"] = c.APIKey
+	allParams["resellerID"] = c.ResellerID
+	for k, v := range params {
+		allParams[k] = v
+	}
+
+	soapRequest, err := createSOAPRequest(genericRequest{method: method, params: allParams})
+	if err != nil {
+		return fmt.Errorf("synergywholesaleapi: failed to build %s request: %w", method, err)
+	}
+
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = defaultEndpoint
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(soapRequest))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/xml; charset=utf-8")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("synergywholesaleapi: %s request failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if err := UnmarshalSOAPResponse(body, out); err != nil {
+		return err
+	}
+
+	return checkStatus(method, out)
+}
+
+// genericRequest marshals an arbitrary method call from a map of params.
+type genericRequest struct {
+	method string
+	params map[string]any
+}
+
+func (r genericRequest) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	keys := make([]string, 0, len(r.params))
+	for k := range r.params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	items := make([]soapParamItem, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, soapParamItem{Key: k, Value: fmt.Sprintf("%v", r.params[k])})
+	}
+
+	return marshalSOAPMethod(e, start, r.method, items)
+}
+
+// APIError is returned by Call when the SOAP response's status field is
+// present and not "OK".
+type APIError struct {
+	Method  string
+	Status  string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("synergywholesaleapi: %s returned status %q: %s", e.Method, e.Status, e.Message)
+}
+
+// checkStatus looks for a "Return" field embedding SOAPResponseCommon (the
+// shape every response in this package follows) and turns a non-OK status
+// into an *APIError. Responses that don't follow that shape are left alone.
+func checkStatus(method string, out any) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	ret := v.FieldByName("Return")
+	if !ret.IsValid() {
+		return nil
+	}
+
+	status := ret.FieldByName("Status")
+	if !status.IsValid() || status.Kind() != reflect.String || status.String() == "" || status.String() == "OK" {
+		return nil
+	}
+
+	message := ret.FieldByName("ErrorMessage")
+	var msg string
+	if message.IsValid() && message.Kind() == reflect.String {
+		msg = message.String()
+	}
+
+	return &APIError{Method: method, Status: status.String(), Message: msg}
+}
@@ -0,0 +1,121 @@
+package synergywholesaleapi
+
+import (
+	"context"
+	"encoding/xml"
+	"log/slog"
+)
+
+// This file adds the DNS zone management operations (listDNSZone,
+// addDNSRecord, deleteDNSRecord) needed to drive ACME DNS-01 challenges.
+
+type soapParamItem struct {
+	Key   string `xml:"key"`
+	Value string `xml:"value"`
+}
+
+// marshalSOAPMethod writes the "ns1:<method>" element with a map of string
+// params. It backs Client.Call's genericRequest.
+func marshalSOAPMethod(e *xml.Encoder, start xml.StartElement, method string, items []soapParamItem) error {
+	start.Name.Local = "ns1:" + method
+	if err := e.EncodeToken(start); err != nil {
+		slog.Error("Error creating SOAP request", "error", err, "method", method)
+		return err
+	}
+
+	paramStart := xml.StartElement{
+		Name: xml.Name{Local: "param"},
+		Attr: []xml.Attr{
+			{Name: xml.Name{Local: "xsi:type"}, Value: "ns2:Map"},
+		},
+	}
+	if err := e.EncodeToken(paramStart); err != nil {
+		slog.Error("Error creating SOAP request", "error", err, "method", method)
+		return err
+	}
+
+	for _, item := range items {
+		if err := e.EncodeElement(item, xml.StartElement{Name: xml.Name{Local: "item"}}); err != nil {
+			return err
+		}
+	}
+
+	if err := e.EncodeToken(paramStart.End()); err != nil {
+		slog.Error("Error creating SOAP request", "error", err, "method", method)
+		return err
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+type ListDNSZoneResponse struct {
+	XMLName xml.Name    `xml:"listDNSZoneResponse"`
+	Return  DNSZoneList `xml:"return"`
+}
+
+type DNSZoneList struct {
+	SOAPResponseCommon
+	Records []DNSZoneRecord `xml:"recordList>item"`
+}
+
+// DNSZoneRecord is a single record as returned by listDNSZone.
+type DNSZoneRecord struct {
+	RecordID string `xml:"record_id"`
+	Type     string `xml:"type"`
+	Host     string `xml:"name"`
+	Value    string `xml:"data"`
+	TTL      int    `xml:"ttl"`
+	Priority int    `xml:"prio,omitempty"`
+}
+
+// ListDNSZone fetches every record in domain's managed zone. It is used to
+// locate the recordID of a previously-added TXT record on CleanUp, since
+// addDNSRecord's response is the only place the recordID is normally seen.
+func ListDNSZone(ctx context.Context, client *Client, domain string) (ListDNSZoneResponse, error) {
+	var response ListDNSZoneResponse
+	err := client.Call(ctx, "listDNSZone", map[string]any{"domainName": domain}, &response)
+	return response, err
+}
+
+type AddDNSRecordResponse struct {
+	XMLName xml.Name           `xml:"addDNSRecordResponse"`
+	Return  AddDNSRecordResult `xml:"return"`
+}
+
+type AddDNSRecordResult struct {
+	SOAPResponseCommon
+	RecordID string `xml:"record_id"`
+}
+
+// AddDNSRecord creates a record in domain's managed zone and returns the
+// resulting recordID. priority is only meaningful for MX records; pass 0
+// otherwise.
+func AddDNSRecord(ctx context.Context, client *Client, domain, recordType, host, value string, ttl, priority int) (AddDNSRecordResponse, error) {
+	params := map[string]any{
+		"domainName": domain,
+		"type":       recordType,
+		"name":       host,
+		"data":       value,
+		"ttl":        ttl,
+	}
+	if priority != 0 {
+		params["prio"] = priority
+	}
+
+	var response AddDNSRecordResponse
+	err := client.Call(ctx, "addDNSRecord", params, &response)
+	return response, err
+}
+
+type DeleteDNSRecordResponse struct {
+	XMLName xml.Name           `xml:"deleteDNSRecordResponse"`
+	Return  SOAPResponseCommon `xml:"return"`
+}
+
+// DeleteDNSRecord removes a single record, identified by recordID, from
+// domain's managed zone.
+func DeleteDNSRecord(ctx context.Context, client *Client, domain, recordID string) (DeleteDNSRecordResponse, error) {
+	var response DeleteDNSRecordResponse
+	err := client.Call(ctx, "deleteDNSRecord", map[string]any{"domainName": domain, "record_id": recordID}, &response)
+	return response, err
+}
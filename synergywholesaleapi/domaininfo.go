@@ -0,0 +1,44 @@
+package synergywholesaleapi
+
+import (
+	"context"
+	"encoding/xml"
+)
+
+type DomainInfoResponse struct {
+	XMLName xml.Name          `xml:"domainInfoResponse"`
+	Return  DomainInfoDetails `xml:"return"`
+}
+
+// DomainInfoDetails holds the subset of domainInfo used for contact-role
+// expiry and domain lock metrics.
+type DomainInfoDetails struct {
+	SOAPResponseCommon
+	DomainName string          `xml:"domainName"`
+	DomainLock string          `xml:"domainLock,omitempty"`
+	Contacts   []ContactDetail `xml:"contacts>item"`
+}
+
+// ContactDetail is a single contact role (registrant, admin, technical,
+// billing) as returned by domainInfo.
+type ContactDetail struct {
+	Role   string `xml:"role"`
+	Expiry string `xml:"expiryDate,omitempty"`
+}
+
+func (contact ContactDetail) GetExpiryTimestamp() int64 {
+	return dateStringToTimestamp(contact.Expiry)
+}
+
+// IsLocked reports whether the domain's registrar lock is enabled.
+func (details DomainInfoDetails) IsLocked() bool {
+	return details.DomainLock == "1" || details.DomainLock == "true"
+}
+
+// GetDomainInfo returns the registrant/contact and lock details for a
+// single domain.
+func GetDomainInfo(ctx context.Context, client *Client, domain string) (DomainInfoResponse, error) {
+	var response DomainInfoResponse
+	err := client.Call(ctx, "domainInfo", map[string]any{"domainName": domain}, &response)
+	return response, err
+}
@@ -0,0 +1,36 @@
+package synergywholesaleapi
+
+import (
+	"context"
+	"encoding/xml"
+)
+
+type ListHostingResponse struct {
+	XMLName xml.Name    `xml:"listHostingResponse"`
+	Return  HostingList `xml:"return"`
+}
+
+type HostingList struct {
+	SOAPResponseCommon
+	Services []HostingService `xml:"hostingList>item"`
+}
+
+// HostingService is a single hosting service as returned by listHosting.
+type HostingService struct {
+	ServiceID string `xml:"serviceID"`
+	Domain    string `xml:"domainName"`
+	Product   string `xml:"product"`
+	Status    string `xml:"status"`
+	Expiry    string `xml:"expiryDate,omitempty"`
+}
+
+func (service HostingService) GetExpiryTimestamp() int64 {
+	return dateStringToTimestamp(service.Expiry)
+}
+
+// ListHosting returns every hosting service on the reseller account.
+func ListHosting(ctx context.Context, client *Client) (ListHostingResponse, error) {
+	var response ListHostingResponse
+	err := client.Call(ctx, "listHosting", nil, &response)
+	return response, err
+}
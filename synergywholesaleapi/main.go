@@ -4,9 +4,7 @@ import (
 	"bytes"
 	"encoding/xml"
 	"fmt"
-	"io"
 	"log/slog"
-	"net/http"
 	"time"
 )
 
@@ -27,59 +25,6 @@ type apiSOAPBody struct {
 	Content interface{} `xml:",any"`
 }
 
-// ListDomainsRequest defines your simple struct
-type ListDomainsRequest struct {
-	ApiKey     string
-	ResellerID string
-}
-
-func (r ListDomainsRequest) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
-	// Start the parent element (listDomains)
-	start.Name.Local = "ns1:listDomains"
-	e.EncodeToken(start)
-
-	// Start the param element with xsi:type attribute
-	paramStart := xml.StartElement{
-		Name: xml.Name{Local: "param"},
-		Attr: []xml.Attr{
-			{Name: xml.Name{Local: "xsi:type"}, Value: "ns2:Map"},
-		},
-	}
-	if err := e.EncodeToken(paramStart); err != nil {
-		slog.Error("Error creating SOAP request", "error", err)
-		return err
-	}
-
-	// Marshal the key-value pairs as nested items
-	items := []struct {
-		Key   string `xml:"key"`
-		Value string `xml:"value"`
-	}{
-		{Key: "apiKey", Value: r.ApiKey},
-		{Key: "resellerID", Value: r.ResellerID},
-	}
-
-	for _, item := range items {
-		if err := e.EncodeElement(item, xml.StartElement{Name: xml.Name{Local: "item"}}); err != nil {
-			return err
-		}
-	}
-
-	// End the param element
-	if err := e.EncodeToken(paramStart.End()); err != nil {
-		slog.Error("Error creating SOAP request", "error", err)
-		return err
-	}
-
-	// End the listDomains element
-	if err := e.EncodeToken(start.End()); err != nil {
-		slog.Error("Error creating SOAP request", "error", err)
-		return err
-	}
-
-	return nil
-}
-
 type ListDomainsResponse struct {
 	XMLName xml.Name   `xml:"listDomainsResponse"`
 	Return  DomainList `xml:"return"`
@@ -138,17 +83,6 @@ type SOAPResponseCommon struct {
 	ErrorMessage string `xml:"errorMessage,omitempty"`
 }
 
-type SOAPResponse struct {
-	XMLName xml.Name `xml:"Envelope"`
-	Body    struct {
-		XMLName  xml.Name `xml:"Body"`
-		Response struct {
-			XMLName xml.Name `xml:"listDomainsResponse"`
-			ListDomainsResponse
-		}
-	}
-}
-
 func createSOAPRequest(request interface{}) ([]byte, error) {
 	envelope := apiSOAPEnvelope{
 		Xmlns:  "http://schemas.xmlsoap.org/soap/envelope/",
@@ -171,54 +105,6 @@ func createSOAPRequest(request interface{}) ([]byte, error) {
 	return xmlRequest, nil
 }
 
-func Send(request ListDomainsRequest) (interface{}, error) {
-
-	response, err := SendSOAPRequest(request)
-	if err != nil {
-		return nil, err
-	}
-
-	// Unmarshal the response
-	var responseObject ListDomainsResponse
-	err2 := UnmarshalSOAPResponse(response, &responseObject)
-	if err2 != nil {
-		return nil, err2
-	}
-	return responseObject, nil
-}
-
-func SendSOAPRequest(param ListDomainsRequest) ([]byte, error) {
-	client := &http.Client{}
-
-	soapRequest, err := createSOAPRequest(param)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", "https://api.synergywholesale.com", bytes.NewBuffer(soapRequest))
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Content-Type", "text/xml; charset=utf-8")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	// Log the response status code
-	slog.Debug("Request successful", "response_code", resp.StatusCode)
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	return body, nil
-}
-
 func UnmarshalSOAPResponse(data []byte, response interface{}) error {
 	envelope := apiSOAPEnvelope{
 		Body: apiSOAPBody{
@@ -0,0 +1,36 @@
+package synergywholesaleapi
+
+import (
+	"context"
+	"encoding/xml"
+)
+
+type ListSSLCertificatesResponse struct {
+	XMLName xml.Name           `xml:"listSSLCertificatesResponse"`
+	Return  SSLCertificateList `xml:"return"`
+}
+
+type SSLCertificateList struct {
+	SOAPResponseCommon
+	Certificates []SSLCertificate `xml:"certificateList>item"`
+}
+
+// SSLCertificate is a single certificate as returned by listSSLCertificates.
+type SSLCertificate struct {
+	Domain  string `xml:"commonName"`
+	CA      string `xml:"certificateAuthority"`
+	Product string `xml:"product"`
+	Status  string `xml:"status"`
+	Expiry  string `xml:"expiryDate,omitempty"`
+}
+
+func (cert SSLCertificate) GetExpiryTimestamp() int64 {
+	return dateStringToTimestamp(cert.Expiry)
+}
+
+// ListSSLCertificates returns every SSL certificate on the reseller account.
+func ListSSLCertificates(ctx context.Context, client *Client) (ListSSLCertificatesResponse, error) {
+	var response ListSSLCertificatesResponse
+	err := client.Call(ctx, "listSSLCertificates", nil, &response)
+	return response, err
+}
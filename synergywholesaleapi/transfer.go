@@ -0,0 +1,26 @@
+package synergywholesaleapi
+
+import (
+	"context"
+	"encoding/xml"
+)
+
+type TransferStatusResponse struct {
+	XMLName xml.Name             `xml:"transferStatusResponse"`
+	Return  TransferStatusResult `xml:"return"`
+}
+
+// TransferStatusResult is the state of an in-progress (or completed) domain
+// transfer, as returned by transferStatus.
+type TransferStatusResult struct {
+	SOAPResponseCommon
+	DomainName string `xml:"domainName"`
+	State      string `xml:"transferStatus"`
+}
+
+// TransferStatus returns the transfer state for a single domain.
+func TransferStatus(ctx context.Context, client *Client, domain string) (TransferStatusResponse, error) {
+	var response TransferStatusResponse
+	err := client.Call(ctx, "transferStatus", map[string]any{"domainName": domain}, &response)
+	return response, err
+}